@@ -3,9 +3,11 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 // DecryptFrontCBC расшифровывает данные, зашифрованные на фронтенде с использованием AES-CBC.
@@ -102,3 +104,75 @@ func (cs *CryptoService) DecryptFrontCBC(encryptedHex, ivHex, dataType string) (
 	// Шаг 11: Возвращаем распарсированный объект
 	return result, nil
 }
+
+// EncryptFrontCBC шифрует строку (обычно JSON-строку) тем же способом, которым
+// DecryptFrontCBC ожидает получать данные с фронтенда: AES-256-CBC с PKCS#7-паддингом
+// и свежим случайным IV.
+//
+// Параметры:
+//   - plaintext: открытый текст (например, результат json.Marshal на стороне вызывающего кода)
+//   - dataType: тип данных, определяющий, какой ключ использовать (например, "FRONT_KEY_1")
+//
+// Возвращает:
+//   - encryptedHex: зашифрованные данные в виде hex-строки
+//   - ivHex: сгенерированный IV в виде hex-строки (нужен получателю для расшифровки)
+//   - error: ошибка, если ключ не найден или шифрование не удалось
+func (cs *CryptoService) EncryptFrontCBC(plaintext string, dataType string) (encryptedHex, ivHex string, err error) {
+	return cs.EncryptFrontCBCBytes([]byte(plaintext), dataType)
+}
+
+// EncryptFrontCBCBytes — то же самое, что и EncryptFrontCBC, но принимает произвольные
+// байты вместо строки. Полезно, когда шифруемые данные не являются JSON (например,
+// уже сериализованный бинарный payload).
+func (cs *CryptoService) EncryptFrontCBCBytes(payload []byte, dataType string) (encryptedHex, ivHex string, err error) {
+	// Шаг 1: Генерируем криптографически безопасный IV размером в один блок AES
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", "", err
+	}
+
+	return cs.encryptFrontCBCWithIV(payload, dataType, iv)
+}
+
+// EncryptFrontCBCWithIV — вариант EncryptFrontCBC с явно заданным IV. Предназначен
+// для детерминированных тестов и сверки с эталонными векторами (например, сгенерированными
+// Node.js через crypto.createCipheriv('aes-256-cbc', key, iv)), где случайный IV сделал бы
+// результат невоспроизводимым. В проде следует использовать EncryptFrontCBC/EncryptFrontCBCBytes.
+func (cs *CryptoService) EncryptFrontCBCWithIV(plaintext string, dataType string, iv []byte) (encryptedHex, ivHex string, err error) {
+	return cs.encryptFrontCBCWithIV([]byte(plaintext), dataType, iv)
+}
+
+// encryptFrontCBCWithIV — общая реализация для EncryptFrontCBC* с уже готовым IV.
+func (cs *CryptoService) encryptFrontCBCWithIV(payload []byte, dataType string, iv []byte) (encryptedHex, ivHex string, err error) {
+	// Шаг 1: Получаем криптографический ключ по имени типа данных
+	key, ok := cs.keys[dataType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownDataType, dataType)
+	}
+
+	// Шаг 2: Проверяем, что длина IV равна размеру блока AES (16 байт)
+	if len(iv) != aes.BlockSize {
+		return "", "", fmt.Errorf("IV must be %d bytes for AES-CBC", aes.BlockSize)
+	}
+
+	// Шаг 3: Создаём AES-шифр на основе 32-байтного ключа (AES-256)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Шаг 4: Добавляем PKCS#7 padding так, чтобы длина была кратна размеру блока
+	padding := aes.BlockSize - len(payload)%aes.BlockSize
+	padded := make([]byte, len(payload)+padding)
+	copy(padded, payload)
+	for i := len(payload); i < len(padded); i++ {
+		padded[i] = byte(padding)
+	}
+
+	// Шаг 5: Шифруем данные "на месте" в режиме CBC с заданным IV
+	mode := cipher.NewCBCEncrypter(block, iv)
+	mode.CryptBlocks(padded, padded)
+
+	// Шаг 6: Кодируем ciphertext и IV в hex
+	return hex.EncodeToString(padded), hex.EncodeToString(iv), nil
+}
@@ -0,0 +1,173 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// Суффиксы, по которым CryptoService ищет в KeyMap ключ шифрования и ключ MAC
+// для аутентифицированного режима CBC-HMAC. Таким образом 64-байтный составной
+// ключ (32 байта MAC + 32 байта шифрования, как в JOSE A256CBC-HS512) хранится
+// как две обычные 32-байтные записи KeyMap, без изменения формата NewCryptoService.
+const (
+	cbcHMACEncKeySuffix = "_ENC"
+	cbcHMACMacKeySuffix = "_MAC"
+)
+
+// CBCHMACResult — результат аутентифицированного шифрования в режиме AES-CBC-HMAC.
+// Повторяет форму EncryptResult (все поля в hex), но дополнительно несёт Tag
+// (аутентификационный код, вычисленный по HMAC-SHA512) и AAD, которые нужны
+// получателю для проверки целостности перед расшифровкой.
+type CBCHMACResult struct {
+	Ciphertext string `json:"ciphertext"`
+	IV         string `json:"iv"`
+	Tag        string `json:"tag"`
+	AAD        string `json:"aad"`
+}
+
+// EncryptCBCHMAC шифрует plaintext в AES-256-CBC и защищает результат
+// HMAC-SHA512-тегом, построенным по образцу JOSE A256CBC-HS512:
+//
+//	MAC = Truncate32( HMAC-SHA512( AAD || IV || ciphertext || AL ) )
+//
+// где AL — это 64-битная big-endian длина AAD в битах. Такой тег закрывает
+// "дыру" в обычном DecryptFrontCBC: атакующий больше не может подменить
+// ciphertext или IV без обнаружения, поскольку тег проверяется ещё до снятия
+// padding'а (см. DecryptCBCHMAC).
+//
+// dataType определяет пару ключей: cs.keys[dataType+"_ENC"] (шифрование) и
+// cs.keys[dataType+"_MAC"] (аутентификация), каждый по 32 байта.
+func (cs *CryptoService) EncryptCBCHMAC(plaintext string, dataType string, aad []byte) (*CBCHMACResult, error) {
+	encKey, macKey, err := cs.cbcHMACKeys(dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Шаг 1: Генерируем случайный IV размером в один блок AES
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	// Шаг 2: PKCS#7-паддинг и шифрование AES-256-CBC
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := make([]byte, len(plaintext)+padding)
+	copy(padded, plaintext)
+	for i := len(plaintext); i < len(padded); i++ {
+		padded[i] = byte(padding)
+	}
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	// Шаг 3: Вычисляем тег по схеме A256CBC-HS512
+	tag := computeCBCHMACTag(macKey, aad, iv, ciphertext)
+
+	return &CBCHMACResult{
+		Ciphertext: hex.EncodeToString(ciphertext),
+		IV:         hex.EncodeToString(iv),
+		Tag:        hex.EncodeToString(tag),
+		AAD:        hex.EncodeToString(aad),
+	}, nil
+}
+
+// DecryptCBCHMAC проверяет тег и расшифровывает данные, созданные EncryptCBCHMAC.
+// Тег сверяется через hmac.Equal (константное время) ДО снятия padding'а и ДО
+// расшифровки — это обязательное условие, чтобы избежать padding-oracle атаки:
+// если бы мы сперва расшифровывали и только потом проверяли тег, поведение
+// ошибок padding'а могло бы дать атакующему побитовый оракул.
+func (cs *CryptoService) DecryptCBCHMAC(ciphertextHex, ivHex, tagHex, aadHex, dataType string) (string, error) {
+	encKey, macKey, err := cs.cbcHMACKeys(dataType)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := hex.DecodeString(ciphertextHex)
+	if err != nil {
+		return "", err
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil {
+		return "", err
+	}
+	tag, err := hex.DecodeString(tagHex)
+	if err != nil {
+		return "", err
+	}
+	aad, err := hex.DecodeString(aadHex)
+	if err != nil {
+		return "", err
+	}
+
+	if len(iv) != aes.BlockSize {
+		return "", fmt.Errorf("IV must be %d bytes for AES-CBC", aes.BlockSize)
+	}
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return "", ErrDecryption
+	}
+
+	// Шаг 1: Проверяем тег ПЕРЕД тем, как трогать ciphertext
+	expectedTag := computeCBCHMACTag(macKey, aad, iv, ciphertext)
+	if !hmac.Equal(tag, expectedTag) {
+		return "", ErrDecryption
+	}
+
+	// Шаг 2: Тег подтверждён — теперь можно безопасно расшифровывать
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Шаг 3: Снимаем PKCS#7 padding
+	padding := plaintext[len(plaintext)-1]
+	if padding == 0 || int(padding) > len(plaintext) {
+		return "", ErrDecryption
+	}
+	for i := 0; i < int(padding); i++ {
+		if plaintext[len(plaintext)-1-i] != padding {
+			return "", ErrDecryption
+		}
+	}
+
+	return string(plaintext[:len(plaintext)-int(padding)]), nil
+}
+
+// computeCBCHMACTag вычисляет MAC = Truncate32(HMAC-SHA512(AAD || IV || ciphertext || AL)),
+// где AL — 64-битная big-endian длина AAD в битах (как того требует A256CBC-HS512).
+func computeCBCHMACTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(sha512.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+
+	return mac.Sum(nil)[:32]
+}
+
+// cbcHMACKeys достаёт пару ключей (шифрование + MAC) для dataType из cs.keys.
+func (cs *CryptoService) cbcHMACKeys(dataType string) (encKey, macKey []byte, err error) {
+	encKey, ok := cs.keys[dataType+cbcHMACEncKeySuffix]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownDataType, dataType+cbcHMACEncKeySuffix)
+	}
+	macKey, ok = cs.keys[dataType+cbcHMACMacKeySuffix]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownDataType, dataType+cbcHMACMacKeySuffix)
+	}
+	return encKey, macKey, nil
+}
@@ -18,9 +18,11 @@ const GCMNonceSize = 16
 // Все поля представлены в виде шестнадцатеричных строк (hex), что позволяет легко
 // передавать их по сети или сохранять в JSON.
 type EncryptResult struct {
-	Encrypted string `json:"encrypted"` // Зашифрованные данные (без auth tag)
-	IV        string `json:"iv"`        // Nonce (вектор инициализации)
-	AuthTag   string `json:"authTag"`   // Аутентификационный тег (для проверки целостности)
+	Encrypted string    `json:"encrypted"`     // Зашифрованные данные (без auth tag)
+	IV        string    `json:"iv"`            // Nonce (вектор инициализации)
+	AuthTag   string    `json:"authTag"`       // Аутентификационный тег (для проверки целостности)
+	Alg       Algorithm `json:"alg,omitempty"` // Алгоритм, которым зашифрованы данные (AlgAESGCM по умолчанию)
+	AAD       string    `json:"aad,omitempty"` // Associated Authenticated Data в hex, если была передана через WithAAD
 }
 
 // Encrypt шифрует открытый текст с использованием AES-GCM.
@@ -35,15 +37,24 @@ type EncryptResult struct {
 //
 // Особенности:
 //   - Используется AES-256-GCM (ключ 32 байта → AES-256)
-//   - Nonce генерируется криптографически безопасным генератором
+//   - Nonce генерируется криптографически безопасным генератором (если не задан через WithNonce)
 //   - AuthTag автоматически вычисляется и отделяется от ciphertext
-func (cs *CryptoService) Encrypt(plaintext string, dataType string) (*EncryptResult, error) {
+//
+// opts принимает функциональные опции WithAAD (привязать ciphertext к
+// дополнительным аутентифицированным данным) и WithNonce (зафиксировать
+// nonce для детерминированных тестов/KAT-векторов вместо случайного).
+func (cs *CryptoService) Encrypt(plaintext string, dataType string, opts ...Option) (*EncryptResult, error) {
 	// Шаг 1: Получаем ключ по имени типа данных
 	key, ok := cs.keys[dataType]
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnknownDataType, dataType)
 	}
 
+	o, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Шаг 2: Создаём базовый AES-шифр (блоковый шифр)
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -57,15 +68,19 @@ func (cs *CryptoService) Encrypt(plaintext string, dataType string) (*EncryptRes
 		return nil, err
 	}
 
-	// Шаг 4: Генерируем криптографически безопасный nonce (IV)
-	nonce := make([]byte, GCMNonceSize)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, err
+	// Шаг 4: Берём nonce из WithNonce (детерминированные тесты) либо генерируем
+	// криптографически безопасный nonce (IV)
+	nonce := o.nonce
+	if nonce == nil {
+		nonce = make([]byte, GCMNonceSize)
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, err
+		}
 	}
 
-	// Шаг 5: Шифруем данные
+	// Шаг 5: Шифруем данные, связывая ciphertext с AAD (если передан через WithAAD)
 	// Метод Seal возвращает: ciphertext + authTag (в одном байтовом срезе)
-	ciphertextWithTag := gcm.Seal(nil, nonce, []byte(plaintext), nil)
+	ciphertextWithTag := gcm.Seal(nil, nonce, []byte(plaintext), o.aad)
 
 	// Шаг 6: Разделяем ciphertext и authTag
 	// Overhead() возвращает длину authTag (обычно 16 байт для GCM)
@@ -73,12 +88,18 @@ func (cs *CryptoService) Encrypt(plaintext string, dataType string) (*EncryptRes
 	authTag := ciphertextWithTag[len(ciphertextWithTag)-tagLen:]
 	encryptedData := ciphertextWithTag[:len(ciphertextWithTag)-tagLen]
 
-	// Шаг 7: Кодируем всё в hex для удобства передачи/хранения
-	return &EncryptResult{
+	result := &EncryptResult{
 		Encrypted: hex.EncodeToString(encryptedData),
 		IV:        hex.EncodeToString(nonce),
 		AuthTag:   hex.EncodeToString(authTag),
-	}, nil
+	}
+	// Шаг 7: Прокладываем AAD в результат, чтобы вызывающий код мог его
+	// залогировать или переслать получателю, не пересчитывая заново
+	if o.aad != nil {
+		result.AAD = hex.EncodeToString(o.aad)
+	}
+
+	return result, nil
 }
 
 // Decrypt расшифровывает данные, зашифрованные с помощью AES-GCM.
@@ -95,13 +116,21 @@ func (cs *CryptoService) Encrypt(plaintext string, dataType string) (*EncryptRes
 //
 // Важно: GCM обеспечивает **аутентифицированное шифрование** — если authTag не совпадает,
 // расшифровка завершится ошибкой, и данные не будут возвращены.
-func (cs *CryptoService) Decrypt(encrypted, iv, authTag, dataType string) (string, error) {
+//
+// Если шифрование выполнялось с WithAAD, тот же AAD нужно передать здесь через
+// WithAAD — иначе gcm.Open вернёт ошибку, даже если ciphertext и authTag верны.
+func (cs *CryptoService) Decrypt(encrypted, iv, authTag, dataType string, opts ...Option) (string, error) {
 	// Шаг 1: Получаем ключ
 	key, ok := cs.keys[dataType]
 	if !ok {
 		return "", fmt.Errorf("%w: %s", ErrUnknownDataType, dataType)
 	}
 
+	o, err := resolveOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
 	// Шаг 2: Декодируем hex-строки в байты
 	encBytes, err := hex.DecodeString(encrypted)
 	if err != nil {
@@ -136,7 +165,7 @@ func (cs *CryptoService) Decrypt(encrypted, iv, authTag, dataType string) (strin
 
 	// Шаг 6: Расшифровываем и одновременно проверяем подлинность
 	// Если authTag не совпадает — вернётся ошибка
-	plaintext, err := gcm.Open(nil, ivBytes, fullCiphertext, nil)
+	plaintext, err := gcm.Open(nil, ivBytes, fullCiphertext, o.aad)
 	if err != nil {
 		// Ошибка может быть вызвана:
 		// - неправильным ключом
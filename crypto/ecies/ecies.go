@@ -0,0 +1,168 @@
+// Package ecies реализует гибридное (асимметричное+симметричное) шифрование
+// в духе ECIES: эфемерная пара ключей P-256 + ECDH для согласования общего
+// секрета, HKDF-SHA256 для вывода из него ключа и nonce, и AES-256-GCM для
+// самого шифрования. В отличие от симметричных режимов crypto.CryptoService,
+// здесь шифрующей стороне достаточно публичного ключа получателя — общий
+// 32-байтный ключ per-dataType пересылать заранее не нужно.
+package ecies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// curve — кривая, на которой строится эфемерный ключ и ECDH. P-256 выбрана
+// как широко поддерживаемая кривая с 32-байтными координатами, что даёт
+// компактный (65 байт) несжатый эфемерный публичный ключ в конверте.
+var curve = elliptic.P256()
+
+// hkdfInfo — контекстная строка HKDF, фиксирующая назначение производных
+// ключей (по аналогии с "BLcrypto/v1/"+dataType в NewCryptoServiceFromMaster).
+const hkdfInfo = "BLcrypto/v1/ecies"
+
+// uncompressedPointSize — длина несжатой точки P-256 (0x04 || X || Y),
+// каждая координата дополнена до 32 байт.
+const uncompressedPointSize = 1 + 32 + 32
+
+// ErrEnvelopeTooShort возвращается, когда конверт короче, чем необходимо для
+// эфемерного публичного ключа.
+var ErrEnvelopeTooShort = errors.New("ecies: envelope too short for ephemeral public key")
+
+// ErrInvalidEphemeralKey возвращается, если эфемерный публичный ключ в
+// конверте не лежит на P-256 (повреждённые данные или не тот конверт).
+var ErrInvalidEphemeralKey = errors.New("ecies: invalid ephemeral public key")
+
+// Encrypt шифрует plaintext для получателя pub: генерирует эфемерную пару
+// P-256, считает общий секрет через ECDH, выводит из него AES-256-GCM
+// ключ+nonce через HKDF-SHA256 и шифрует plaintext с заданным aad.
+// Возвращает конверт вида ephemeralPubUncompressed || ciphertext || tag.
+func Encrypt(pub *ecdsa.PublicKey, plaintext, aad []byte) ([]byte, error) {
+	ephPriv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, nonce, err := deriveKeyNonce(ephPriv, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := sealGCM(key, nonce, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	ephPub := elliptic.Marshal(curve, ephPriv.PublicKey.X, ephPriv.PublicKey.Y)
+	envelope := make([]byte, 0, len(ephPub)+len(ciphertext))
+	envelope = append(envelope, ephPub...)
+	envelope = append(envelope, ciphertext...)
+	return envelope, nil
+}
+
+// Decrypt разбирает конверт, созданный Encrypt: достаёт эфемерный публичный
+// ключ, заново считает общий секрет через ECDH (уже с приватным ключом
+// получателя) и расшифровывает оставшиеся байты как AES-256-GCM.
+func Decrypt(priv *ecdsa.PrivateKey, envelope, aad []byte) ([]byte, error) {
+	if len(envelope) < uncompressedPointSize {
+		return nil, ErrEnvelopeTooShort
+	}
+
+	ephPubBytes := envelope[:uncompressedPointSize]
+	ciphertext := envelope[uncompressedPointSize:]
+
+	ephX, ephY := elliptic.Unmarshal(curve, ephPubBytes)
+	if ephX == nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+	ephPub := &ecdsa.PublicKey{Curve: curve, X: ephX, Y: ephY}
+
+	key, nonce, err := deriveKeyNonceFromPriv(priv, ephPub)
+	if err != nil {
+		return nil, err
+	}
+
+	return openGCM(key, nonce, ciphertext, aad)
+}
+
+// deriveKeyNonce считает общий секрет ECDH(ephPriv, pub) и выводит из него
+// AES-256-GCM ключ (32 байта) и nonce (gcmNonceSize байт) через HKDF-SHA256.
+// Используется на стороне шифрования, где есть только что сгенерированный
+// эфемерный приватный ключ.
+func deriveKeyNonce(ephPriv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) (key, nonce []byte, err error) {
+	return deriveFromSharedSecret(ecdh(ephPriv, pub))
+}
+
+// deriveKeyNonceFromPriv — то же самое, но на стороне расшифровки, где
+// эфемерный публичный ключ пришёл из конверта, а приватный ключ — это ключ
+// получателя.
+func deriveKeyNonceFromPriv(priv *ecdsa.PrivateKey, ephPub *ecdsa.PublicKey) (key, nonce []byte, err error) {
+	return deriveFromSharedSecret(ecdh(priv, ephPub))
+}
+
+// ecdh вычисляет общую X-координату ECDH: priv.D * pub, дополненную нулями
+// слева до размера координаты кривой (32 байта для P-256).
+func ecdh(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) []byte {
+	sx, _ := pub.Curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+
+	shared := make([]byte, (pub.Curve.Params().BitSize+7)/8)
+	sxBytes := sx.Bytes()
+	copy(shared[len(shared)-len(sxBytes):], sxBytes)
+	return shared
+}
+
+// deriveFromSharedSecret выводит ключ шифрования (32 байта) и nonce
+// (gcmNonceSize байт) из общего ECDH-секрета через HKDF-SHA256 с контекстной
+// строкой hkdfInfo.
+func deriveFromSharedSecret(sharedSecret []byte) (key, nonce []byte, err error) {
+	reader := hkdf.New(sha256.New, sharedSecret, nil, []byte(hkdfInfo))
+
+	derived := make([]byte, 32+gcmNonceSize)
+	if _, err := io.ReadFull(reader, derived); err != nil {
+		return nil, nil, err
+	}
+
+	return derived[:32], derived[32:], nil
+}
+
+// gcmNonceSize — стандартный (рекомендованный NIST) размер nonce для AES-GCM.
+// Сознательно не переиспользует crypto.GCMNonceSize (16 байт): пакет ecies не
+// зависит от crypto, чтобы избежать циклического импорта (crypto сам вызывает
+// ecies из WrapKey/UnwrapKey).
+const gcmNonceSize = 12
+
+func sealGCM(key, nonce, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func openGCM(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("ecies: decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, gcmNonceSize)
+}
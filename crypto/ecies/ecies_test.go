@@ -0,0 +1,66 @@
+package ecies
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+)
+
+func TestEncryptDecrypt_RoundTrips(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	plaintext := []byte("a 32-byte symmetric key goes.ab")
+	aad := []byte("dataType:USER_EMAIL")
+
+	envelope, err := Encrypt(&priv.PublicKey, plaintext, aad)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	got, err := Decrypt(priv, envelope, aad)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("Expected %q, got %q", plaintext, got)
+	}
+}
+
+func TestDecrypt_WrongPrivateKeyFails(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	envelope, err := Encrypt(&priv.PublicKey, []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if _, err := Decrypt(other, envelope, nil); err == nil {
+		t.Fatal("Expected error when decrypting with the wrong private key")
+	}
+}
+
+func TestDecrypt_MismatchedAADFails(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	envelope, err := Encrypt(&priv.PublicKey, []byte("secret"), []byte("ctx-a"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if _, err := Decrypt(priv, envelope, []byte("ctx-b")); err == nil {
+		t.Fatal("Expected error for mismatched AAD")
+	}
+}
+
+func TestDecrypt_TruncatedEnvelopeFails(t *testing.T) {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if _, err := Decrypt(priv, []byte{0x04, 0x01, 0x02}, nil); err != ErrEnvelopeTooShort {
+		t.Errorf("Expected ErrEnvelopeTooShort, got: %v", err)
+	}
+}
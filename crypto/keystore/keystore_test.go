@@ -0,0 +1,168 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+// testParams keeps scrypt cheap enough for tests to run quickly; production
+// code should use DefaultScryptParams (N=1<<18).
+var testParams = ScryptParams{N: 1 << 12, R: 8, P: 1, DKLen: dkLen}
+
+func TestSaveLoad_RoundTrips(t *testing.T) {
+	emailKey := make([]byte, 32)
+	apiKey := make([]byte, 32)
+	for i := range apiKey {
+		apiKey[i] = byte(i + 1)
+	}
+
+	cs, err := crypto.NewCryptoService(crypto.KeyMap{
+		"USER_EMAIL": emailKey,
+		"API_KEY":    apiKey,
+	})
+	if err != nil {
+		t.Fatalf("NewCryptoService failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := SaveWithParams(cs, "correct horse battery staple", path, testParams); err != nil {
+		t.Fatalf("SaveWithParams failed: %v", err)
+	}
+
+	loaded, err := Load("correct horse battery staple", path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	got := loaded.ExportKeys()
+	if hex.EncodeToString(got["USER_EMAIL"]) != hex.EncodeToString(emailKey) {
+		t.Errorf("USER_EMAIL key mismatch after round trip")
+	}
+	if hex.EncodeToString(got["API_KEY"]) != hex.EncodeToString(apiKey) {
+		t.Errorf("API_KEY key mismatch after round trip")
+	}
+}
+
+func TestLoad_WrongPassphraseFails(t *testing.T) {
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"API_KEY": make([]byte, 32)})
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := SaveWithParams(cs, "correct-password", path, testParams); err != nil {
+		t.Fatalf("SaveWithParams failed: %v", err)
+	}
+
+	if _, err := Load("wrong-password", path); err == nil {
+		t.Fatal("Expected error when loading with the wrong passphrase")
+	}
+}
+
+func TestLoad_TamperedCiphertextFails(t *testing.T) {
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"API_KEY": make([]byte, 32)})
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := SaveWithParams(cs, "correct-password", path, testParams); err != nil {
+		t.Fatalf("SaveWithParams failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	tampered := append([]byte(nil), data...)
+	for i, b := range tampered {
+		if b == '0' {
+			tampered[i] = '1'
+			break
+		}
+	}
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, err := Load("correct-password", path); err == nil {
+		t.Fatal("Expected error when ciphertext is tampered with")
+	}
+}
+
+// TestDecryptKey_RejectsInvalidDKLen guards against a regression where a
+// negative/too-small dklen from the file reaches scrypt.Key (or the
+// subsequent dk[:16]/dk[16:32] split) and panics instead of returning an
+// error — this happens before the MAC is even checked, so it doesn't
+// require a valid passphrase or MAC to trigger.
+func TestDecryptKey_RejectsInvalidDKLen(t *testing.T) {
+	entry, err := encryptKeyWithSaltIV(make([]byte, 32), "pw", testParams, make([]byte, saltSize), make([]byte, 16))
+	if err != nil {
+		t.Fatalf("encryptKeyWithSaltIV failed: %v", err)
+	}
+
+	for _, dkLen := range []int{-1, 0, 16} {
+		entry.KDFParams.DKLen = dkLen
+		if _, err := decryptKey(*entry, "pw"); err == nil {
+			t.Errorf("Expected decryptKey to reject dklen=%d, got no error", dkLen)
+		}
+	}
+}
+
+// TestDecryptKey_RejectsInvalidIVLength guards against a regression where an
+// attacker-flipped IV (the MAC covers only macKey||ciphertext, not the IV,
+// matching the real V3 format) of the wrong length reaches cipher.NewCTR and
+// panics instead of returning an error.
+func TestDecryptKey_RejectsInvalidIVLength(t *testing.T) {
+	entry, err := encryptKeyWithSaltIV(make([]byte, 32), "pw", testParams, make([]byte, saltSize), make([]byte, 16))
+	if err != nil {
+		t.Fatalf("encryptKeyWithSaltIV failed: %v", err)
+	}
+
+	entry.CipherParams.IV = hex.EncodeToString(make([]byte, 8))
+	if _, err := decryptKey(*entry, "pw"); err == nil {
+		t.Error("Expected decryptKey to reject a truncated IV, got no error")
+	}
+}
+
+// TestEncryptKeyWithSaltIV_FixedVector pins salt/IV/passphrase so the
+// scrypt+AES-128-CTR+Keccak256 pipeline can be regenerated and cross-checked
+// against go-ethereum's keystore (same KDF, cipher and MAC construction).
+func TestEncryptKeyWithSaltIV_FixedVector(t *testing.T) {
+	passphrase := "correct horse battery staple"
+	salt := make([]byte, saltSize)
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	iv := make([]byte, 16)
+	for i := range iv {
+		iv[i] = byte(0x10 + i)
+	}
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(0xA0 + i)
+	}
+
+	const (
+		wantCiphertext = "00b895d79478a7fdfc9eff48429a705756a144a29a67d760b45d560f65cf20b3"
+		wantMAC        = "8a2d9dfba5fe823b1a8b8be052dc984606ae2c2776b9972566e88adb95d9223b"
+	)
+
+	entry, err := encryptKeyWithSaltIV(key, passphrase, testParams, salt, iv)
+	if err != nil {
+		t.Fatalf("encryptKeyWithSaltIV failed: %v", err)
+	}
+
+	if entry.CipherText != wantCiphertext {
+		t.Errorf("ciphertext mismatch: got %s, want %s", entry.CipherText, wantCiphertext)
+	}
+	if entry.MAC != wantMAC {
+		t.Errorf("mac mismatch: got %s, want %s", entry.MAC, wantMAC)
+	}
+
+	got, err := decryptKey(*entry, passphrase)
+	if err != nil {
+		t.Fatalf("decryptKey failed: %v", err)
+	}
+	if hex.EncodeToString(got) != hex.EncodeToString(key) {
+		t.Errorf("decrypted key mismatch: got %x, want %x", got, key)
+	}
+}
@@ -0,0 +1,72 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// masterKeyInfoPrefix — префикс контекстной строки HKDF для NewCryptoServiceFromMaster.
+// Совпадает с обоснованием "изоляции по dataType", уже описанным для
+// combinedHash* в hashing.go: даже если один производный ключ утечёт, HKDF
+// с другим info-значением не позволяет восстановить остальные.
+const masterKeyInfoPrefix = "BLcrypto/v1/"
+
+// NewCryptoServiceFromMaster строит CryptoService из одного мастер-секрета
+// вместо отдельного 32-байтного ключа на каждый dataType: для каждого имени
+// из dataTypes HKDF-SHA256 выводит независимый 32-байтный подключ с
+// info = "BLcrypto/v1/"+dataType. salt задаётся вызывающим кодом (например,
+// версия/окружение деплоя) и должен быть одинаковым при каждом вызове, иначе
+// подключи не совпадут между запусками.
+func NewCryptoServiceFromMaster(master []byte, dataTypes []string, salt []byte) (*CryptoService, error) {
+	keys := make(KeyMap, len(dataTypes))
+	for _, dataType := range dataTypes {
+		key, err := deriveMasterSubkey(master, salt, dataType)
+		if err != nil {
+			return nil, err
+		}
+		keys[dataType] = key
+	}
+
+	return NewCryptoService(keys)
+}
+
+// RotateMaster выводит новый CryptoService из newMaster, сохраняя тот же
+// набор dataType и тот же выбор алгоритма (cs.algs, если cs был собран через
+// NewCryptoServiceWithAlgorithms), что был зарегистрирован в cs. Возвращает
+// отдельный сервис, а не мутирует cs — так вызывающий код может держать
+// старый и новый сервис одновременно на время переключения получателей на
+// новые ключи.
+func (cs *CryptoService) RotateMaster(newMaster, salt []byte) (*CryptoService, error) {
+	dataTypes := make([]string, 0, len(cs.keys))
+	for dataType := range cs.keys {
+		dataTypes = append(dataTypes, dataType)
+	}
+
+	rotated, err := NewCryptoServiceFromMaster(newMaster, dataTypes, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if cs.algs != nil {
+		rotated.algs = make(map[string]Algorithm, len(cs.algs))
+		for dataType, alg := range cs.algs {
+			rotated.algs[dataType] = alg
+		}
+	}
+
+	return rotated, nil
+}
+
+// deriveMasterSubkey выводит 32-байтный подключ для одного dataType через
+// HKDF-SHA256(master, salt, info="BLcrypto/v1/"+dataType).
+func deriveMasterSubkey(master, salt []byte, dataType string) ([]byte, error) {
+	reader := hkdf.New(sha256.New, master, salt, []byte(masterKeyInfoPrefix+dataType))
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
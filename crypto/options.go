@@ -0,0 +1,50 @@
+package crypto
+
+import "fmt"
+
+// encryptOptions собирает значения, переданные через функциональные опции
+// Encrypt/Decrypt (WithAAD, WithNonce). Нулевое значение (nil AAD, nil nonce)
+// воспроизводит прежнее поведение: AAD = nil, nonce генерируется случайно.
+type encryptOptions struct {
+	aad   []byte
+	nonce []byte
+}
+
+// Option настраивает поведение Encrypt/Decrypt в стиле функциональных опций
+// (см. WithCustomNonce в nip44).
+type Option func(*encryptOptions)
+
+// WithAAD привязывает ciphertext к дополнительным аутентифицированным данным
+// (Associated Authenticated Data) — например, ID пользователя, путь запроса
+// или tenant. AAD участвует в gcm.Seal/gcm.Open, но не шифруется: если при
+// расшифровке передать другой AAD (или не передать вовсе), Decrypt вернёт
+// ErrDecryption.
+func WithAAD(aad []byte) Option {
+	return func(o *encryptOptions) {
+		o.aad = aad
+	}
+}
+
+// WithNonce задаёт фиксированный nonce вместо случайного. Предназначена
+// исключительно для детерминированных тестов и проверки по известным
+// векторам (KAT) — при шифровании реальных данных всегда используйте
+// случайный nonce (опцию не передавайте), иначе переиспользование nonce с
+// одним и тем же ключом полностью ломает конфиденциальность GCM.
+func WithNonce(nonce []byte) Option {
+	return func(o *encryptOptions) {
+		o.nonce = nonce
+	}
+}
+
+func resolveOptions(opts []Option) (*encryptOptions, error) {
+	o := &encryptOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.nonce != nil && len(o.nonce) != GCMNonceSize {
+		return nil, fmt.Errorf("WithNonce: nonce must be %d bytes, got %d", GCMNonceSize, len(o.nonce))
+	}
+
+	return o, nil
+}
@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KeyMap сопоставляет dataType (например, "USER_EMAIL", "FRONT_KEY_1") с
+// 32-байтным ключом (AES-256 / HMAC-SHA256 ключ, в зависимости от того, где
+// используется). Один и тот же dataType может одновременно встречаться в
+// hashConfig (для HashData) и использоваться для Encrypt/Decrypt — ключи
+// для разных целей должны иметь разные имена dataType.
+type KeyMap map[string][]byte
+
+// CryptoService — основной сервис, инкапсулирующий набор ключей проекта.
+// Конкретные операции (Encrypt/Decrypt, DecryptFrontCBC, HashData и т.д.)
+// реализованы как методы CryptoService в соседних файлах пакета.
+//
+// algs хранит выбранный AEAD-алгоритм для тех dataType, что были
+// зарегистрированы через NewCryptoServiceWithAlgorithms. Для dataType,
+// отсутствующих в algs (в т.ч. для всех ключей, заведённых через обычный
+// NewCryptoService), действует алгоритм по умолчанию — AlgAESGCM, что
+// сохраняет поведение существующих вызывающих кодов без изменений.
+//
+// keys и algs заполняются один раз конструктором (NewCryptoService,
+// NewCryptoServiceWithAlgorithms, NewCryptoServiceFromMaster) и после этого
+// только читаются — благодаря этому один и тот же *CryptoService можно
+// свободно передавать между горутинами. Единственное исключение — UnwrapKey,
+// который дописывает keys уже после конструктора; см. её doc-комментарий.
+type CryptoService struct {
+	keys KeyMap
+	algs map[string]Algorithm
+}
+
+var (
+	// ErrUnknownDataType возвращается, когда для переданного dataType нет
+	// зарегистрированного ключа (или, для HashData, конфигурации хеша).
+	ErrUnknownDataType = errors.New("unknown data type")
+
+	// ErrDecryption — общая ошибка неудачной расшифровки (неверный ключ,
+	// повреждённые данные, несовпадение authTag/MAC, невалидный padding).
+	// Конкретная причина намеренно не раскрывается вызывающему коду.
+	ErrDecryption = errors.New("decryption failed")
+
+	// ErrInvalidKeySize возвращается NewCryptoService, если хотя бы один
+	// ключ в KeyMap не равен 32 байтам (размер ключа AES-256).
+	ErrInvalidKeySize = errors.New("invalid key size: expected 32 bytes")
+)
+
+// NewCryptoService проверяет, что все ключи в keys имеют длину 32 байта
+// (AES-256 / HMAC-SHA256), и возвращает готовый к использованию CryptoService.
+func NewCryptoService(keys KeyMap) (*CryptoService, error) {
+	for dataType, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w: %s (%d bytes)", ErrInvalidKeySize, dataType, len(key))
+		}
+	}
+
+	return &CryptoService{keys: keys}, nil
+}
+
+// ExportKeys возвращает копию ключей, которыми управляет cs. Предназначена
+// для подпакетов вроде keystore, которым нужно сериализовать KeyMap на диск
+// (зашифрованным) — она отдаёт сырой key-материал, поэтому результат нельзя
+// логировать или передавать куда-либо в открытом виде.
+func (cs *CryptoService) ExportKeys() KeyMap {
+	out := make(KeyMap, len(cs.keys))
+	for dataType, key := range cs.keys {
+		keyCopy := make([]byte, len(key))
+		copy(keyCopy, key)
+		out[dataType] = keyCopy
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/BLAgency/BLcrypto/crypto/ecies"
+)
+
+// WrapKey шифрует 32-байтный ключ, зарегистрированный под dataType, для
+// получателя peerPub с помощью ecies.Encrypt — получатель сможет
+// восстановить ключ через UnwrapKey, зная только свой приватный ключ.
+// dataType передаётся как AAD, поэтому конверт привязан к конкретному имени
+// ключа и не может быть тихо подставлен под другой dataType.
+func (cs *CryptoService) WrapKey(dataType string, peerPub *ecdsa.PublicKey) ([]byte, error) {
+	key, ok := cs.keys[dataType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDataType, dataType)
+	}
+
+	return ecies.Encrypt(peerPub, key, []byte(dataType))
+}
+
+// UnwrapKey расшифровывает конверт, созданный WrapKey (или напрямую
+// ecies.Encrypt с тем же AAD), и регистрирует полученный ключ под dataType —
+// после успешного вызова cs можно использовать для Encrypt/Decrypt/HashData
+// и т.д. с этим dataType, как если бы ключ был передан в NewCryptoService.
+//
+// UnwrapKey пишет в cs.keys без блокировки, в отличие от остальных методов
+// CryptoService, которые только читают keys/algs, заполненные один раз в
+// конструкторе (см. doc-комментарий CryptoService). Поэтому вызывающий код
+// обязан гарантировать, что UnwrapKey не выполняется одновременно с любым
+// другим методом этого cs (включая другой UnwrapKey/WrapKey) — например,
+// вызывая его до публикации *CryptoService в другие горутины, либо защищая
+// его собственной блокировкой снаружи.
+func (cs *CryptoService) UnwrapKey(dataType string, envelope []byte, priv *ecdsa.PrivateKey) error {
+	key, err := ecies.Decrypt(priv, envelope, []byte(dataType))
+	if err != nil {
+		return err
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("%w: %s (%d bytes)", ErrInvalidKeySize, dataType, len(key))
+	}
+
+	if cs.keys == nil {
+		cs.keys = make(KeyMap)
+	}
+	cs.keys[dataType] = key
+	return nil
+}
@@ -0,0 +1,88 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+func TestCBCHMAC_EncryptDecrypt_Success(t *testing.T) {
+	encKey := make([]byte, 32)
+	macKey := make([]byte, 32)
+	for i := range macKey {
+		macKey[i] = byte(i + 1)
+	}
+
+	cs, err := crypto.NewCryptoService(crypto.KeyMap{
+		"SESSION_ENC": encKey,
+		"SESSION_MAC": macKey,
+	})
+	if err != nil {
+		t.Fatalf("NewCryptoService failed: %v", err)
+	}
+
+	aad := []byte("user:42")
+	plaintext := `{"userId":42,"role":"admin"}`
+
+	result, err := cs.EncryptCBCHMAC(plaintext, "SESSION", aad)
+	if err != nil {
+		t.Fatalf("EncryptCBCHMAC failed: %v", err)
+	}
+
+	if result.Ciphertext == "" || result.IV == "" || result.Tag == "" {
+		t.Fatal("EncryptCBCHMAC returned empty fields")
+	}
+
+	dec, err := cs.DecryptCBCHMAC(result.Ciphertext, result.IV, result.Tag, result.AAD, "SESSION")
+	if err != nil {
+		t.Fatalf("DecryptCBCHMAC failed: %v", err)
+	}
+	if dec != plaintext {
+		t.Errorf("Expected %q, got %q", plaintext, dec)
+	}
+}
+
+func TestCBCHMAC_TamperedCiphertext_Rejected(t *testing.T) {
+	encKey := make([]byte, 32)
+	macKey := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{
+		"SESSION_ENC": encKey,
+		"SESSION_MAC": macKey,
+	})
+
+	result, _ := cs.EncryptCBCHMAC("secret", "SESSION", nil)
+
+	tampered := "ff" + result.Ciphertext[2:]
+	_, err := cs.DecryptCBCHMAC(tampered, result.IV, result.Tag, result.AAD, "SESSION")
+	if err == nil {
+		t.Fatal("Expected error for tampered ciphertext")
+	}
+	if err != crypto.ErrDecryption {
+		t.Errorf("Expected ErrDecryption, got: %v", err)
+	}
+}
+
+func TestCBCHMAC_TamperedAAD_Rejected(t *testing.T) {
+	encKey := make([]byte, 32)
+	macKey := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{
+		"SESSION_ENC": encKey,
+		"SESSION_MAC": macKey,
+	})
+
+	result, _ := cs.EncryptCBCHMAC("secret", "SESSION", []byte("user:42"))
+
+	_, err := cs.DecryptCBCHMAC(result.Ciphertext, result.IV, result.Tag, "", "SESSION")
+	if err == nil {
+		t.Fatal("Expected error when AAD does not match what was authenticated")
+	}
+}
+
+func TestCBCHMAC_MissingKeys(t *testing.T) {
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"OTHER_ENC": make([]byte, 32)})
+
+	_, err := cs.EncryptCBCHMAC("secret", "SESSION", nil)
+	if err == nil {
+		t.Fatal("Expected error for missing enc/mac keys")
+	}
+}
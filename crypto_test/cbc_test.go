@@ -66,3 +66,86 @@ func TestCBC_InvalidPadding(t *testing.T) {
 		t.Fatal("Expected error due to invalid padding")
 	}
 }
+
+func TestCBC_EncryptFront_RoundTripsWithDecryptFront(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + 5)
+	}
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"FRONT_KEY_1": key})
+
+	payload := `{"userId":42,"action":"login"}`
+	encryptedHex, ivHex, err := cs.EncryptFrontCBC(payload, "FRONT_KEY_1")
+	if err != nil {
+		t.Fatalf("EncryptFrontCBC failed: %v", err)
+	}
+
+	result, err := cs.DecryptFrontCBC(encryptedHex, ivHex, "FRONT_KEY_1")
+	if err != nil {
+		t.Fatalf("DecryptFrontCBC failed: %v", err)
+	}
+
+	if userId, ok := result["userId"].(float64); !ok || int(userId) != 42 {
+		t.Errorf("Expected userId=42, got %+v", result)
+	}
+}
+
+func TestCBC_EncryptFrontBytes_RoundTrips(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"FRONT_KEY_1": key})
+
+	payload := []byte{0x01, 0x02, 0x03, 0xff, 0x00}
+	encryptedHex, ivHex, err := cs.EncryptFrontCBCBytes(payload, "FRONT_KEY_1")
+	if err != nil {
+		t.Fatalf("EncryptFrontCBCBytes failed: %v", err)
+	}
+
+	encrypted, _ := hex.DecodeString(encryptedHex)
+	iv, _ := hex.DecodeString(ivHex)
+	block, _ := aes.NewCipher(key)
+	mode := cipher.NewCBCDecrypter(block, iv)
+	decrypted := make([]byte, len(encrypted))
+	mode.CryptBlocks(decrypted, encrypted)
+	padding := decrypted[len(decrypted)-1]
+	decrypted = decrypted[:len(decrypted)-int(padding)]
+
+	if hex.EncodeToString(decrypted) != hex.EncodeToString(payload) {
+		t.Errorf("Expected %x, got %x", payload, decrypted)
+	}
+}
+
+func TestCBC_EncryptFrontWithIV_MatchesNodeFixture(t *testing.T) {
+	// Fixture produced by Node.js:
+	//   crypto.createCipheriv('aes-256-cbc', key, iv).update(plaintext) + .final()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + 1)
+	}
+	iv := make([]byte, aes.BlockSize)
+	for i := range iv {
+		iv[i] = byte(i)
+	}
+	const wantEncryptedHex = "3dba0caa3940c79e5a67abbc113799121f7bb7132093cf0ec0efba15e490b438"
+	payload := `{"userId":7,"action":"logout"}`
+
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"FRONT_KEY_1": key})
+	encryptedHex, ivHex, err := cs.EncryptFrontCBCWithIV(payload, "FRONT_KEY_1", iv)
+	if err != nil {
+		t.Fatalf("EncryptFrontCBCWithIV failed: %v", err)
+	}
+
+	if encryptedHex != wantEncryptedHex {
+		t.Errorf("Expected %s, got %s", wantEncryptedHex, encryptedHex)
+	}
+	if ivHex != hex.EncodeToString(iv) {
+		t.Errorf("Expected ivHex %s, got %s", hex.EncodeToString(iv), ivHex)
+	}
+
+	result, err := cs.DecryptFrontCBC(encryptedHex, ivHex, "FRONT_KEY_1")
+	if err != nil {
+		t.Fatalf("DecryptFrontCBC failed: %v", err)
+	}
+	if result["action"] != "logout" {
+		t.Errorf("Expected action=logout, got %+v", result)
+	}
+}
@@ -0,0 +1,82 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+func TestChaCha20_EncryptDecrypt_Success(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i + 1)
+	}
+
+	cs, err := crypto.NewCryptoServiceWithAlgorithms(crypto.AlgKeyMap{
+		"MOBILE_TOKEN": {Key: key, Alg: crypto.AlgChaCha20Poly1305},
+	})
+	if err != nil {
+		t.Fatalf("NewCryptoServiceWithAlgorithms failed: %v", err)
+	}
+
+	plaintext := "refresh-token-value"
+	enc, err := cs.EncryptWithAlg(plaintext, "MOBILE_TOKEN")
+	if err != nil {
+		t.Fatalf("EncryptWithAlg failed: %v", err)
+	}
+	if enc.Alg != crypto.AlgChaCha20Poly1305 {
+		t.Errorf("Expected Alg=AlgChaCha20Poly1305, got %v", enc.Alg)
+	}
+	if len(enc.IV) != crypto.ChaChaNonceSize*2 {
+		t.Errorf("Expected %d-byte nonce, got hex of length %d", crypto.ChaChaNonceSize, len(enc.IV))
+	}
+
+	dec, err := cs.DecryptWithAlg(enc, "MOBILE_TOKEN")
+	if err != nil {
+		t.Fatalf("DecryptWithAlg failed: %v", err)
+	}
+	if dec != plaintext {
+		t.Errorf("Expected %q, got %q", plaintext, dec)
+	}
+}
+
+func TestChaCha20_DefaultsToAESGCMForPlainKeyMap(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	enc, err := cs.EncryptWithAlg("user@example.com", "EMAIL")
+	if err != nil {
+		t.Fatalf("EncryptWithAlg failed: %v", err)
+	}
+	if enc.Alg != crypto.AlgAESGCM {
+		t.Errorf("Expected Alg=AlgAESGCM by default, got %v", enc.Alg)
+	}
+	if len(enc.IV) != crypto.GCMNonceSize*2 {
+		t.Errorf("Expected %d-byte nonce, got hex of length %d", crypto.GCMNonceSize, len(enc.IV))
+	}
+}
+
+func TestChaCha20_InvalidKeySize(t *testing.T) {
+	_, err := crypto.NewCryptoServiceWithAlgorithms(crypto.AlgKeyMap{
+		"SHORT": {Key: []byte("too-short"), Alg: crypto.AlgChaCha20Poly1305},
+	})
+	if err == nil {
+		t.Fatal("Expected error for short ChaCha20-Poly1305 key")
+	}
+}
+
+func TestChaCha20_RejectsWrongNonceSize(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoServiceWithAlgorithms(crypto.AlgKeyMap{
+		"MOBILE_TOKEN": {Key: key, Alg: crypto.AlgChaCha20Poly1305},
+	})
+
+	enc, _ := cs.EncryptWithAlg("secret", "MOBILE_TOKEN")
+	// Simulate a GCM-sized (16-byte) nonce being fed into the ChaCha20 path.
+	enc.IV = enc.IV + "00000000"
+
+	_, err := cs.DecryptWithAlg(enc, "MOBILE_TOKEN")
+	if err == nil {
+		t.Fatal("Expected error for mismatched nonce size")
+	}
+}
@@ -0,0 +1,128 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+func TestNewCryptoServiceFromMaster_IsolatesSubkeysPerDataType(t *testing.T) {
+	master := []byte("a master secret of arbitrary length")
+	salt := []byte("deployment-salt")
+
+	cs, err := crypto.NewCryptoServiceFromMaster(master, []string{"USER_EMAIL", "API_KEY"}, salt)
+	if err != nil {
+		t.Fatalf("NewCryptoServiceFromMaster failed: %v", err)
+	}
+
+	keys := cs.ExportKeys()
+	if len(keys["USER_EMAIL"]) != 32 || len(keys["API_KEY"]) != 32 {
+		t.Fatal("Expected both subkeys to be 32 bytes")
+	}
+	if hex.EncodeToString(keys["USER_EMAIL"]) == hex.EncodeToString(keys["API_KEY"]) {
+		t.Error("Expected different dataTypes to derive different subkeys")
+	}
+}
+
+func TestNewCryptoServiceFromMaster_Deterministic(t *testing.T) {
+	master := []byte("a master secret of arbitrary length")
+	salt := []byte("deployment-salt")
+
+	cs1, _ := crypto.NewCryptoServiceFromMaster(master, []string{"USER_EMAIL"}, salt)
+	cs2, _ := crypto.NewCryptoServiceFromMaster(master, []string{"USER_EMAIL"}, salt)
+
+	key1 := cs1.ExportKeys()["USER_EMAIL"]
+	key2 := cs2.ExportKeys()["USER_EMAIL"]
+	if hex.EncodeToString(key1) != hex.EncodeToString(key2) {
+		t.Error("Expected the same master+salt+dataType to derive the same subkey every time")
+	}
+}
+
+// TestNewCryptoServiceFromMaster_StableVector pins master/salt so the derived
+// subkeys can be reproduced in other languages/implementations.
+func TestNewCryptoServiceFromMaster_StableVector(t *testing.T) {
+	master := []byte("0123456789abcdef0123456789abcdef")
+	salt := []byte("BLcrypto-test-salt")
+
+	const (
+		wantUserEmail = "ce3d8abafb478e363c608ad5d89bd994c682ee6c3f34d321986c56f4147529e2"
+		wantAPIKey    = "c4eedb7575bbb6021295364dbc1bfcc9b9437ff163795f8cb21fc5954a864185"
+	)
+
+	cs, err := crypto.NewCryptoServiceFromMaster(master, []string{"USER_EMAIL", "API_KEY"}, salt)
+	if err != nil {
+		t.Fatalf("NewCryptoServiceFromMaster failed: %v", err)
+	}
+
+	keys := cs.ExportKeys()
+	if got := hex.EncodeToString(keys["USER_EMAIL"]); got != wantUserEmail {
+		t.Errorf("USER_EMAIL subkey mismatch: got %s, want %s", got, wantUserEmail)
+	}
+	if got := hex.EncodeToString(keys["API_KEY"]); got != wantAPIKey {
+		t.Errorf("API_KEY subkey mismatch: got %s, want %s", got, wantAPIKey)
+	}
+}
+
+func TestRotateMaster_PreservesDataTypesWithNewKeys(t *testing.T) {
+	oldMaster := []byte("old master secret")
+	newMaster := []byte("new master secret")
+	salt := []byte("deployment-salt")
+
+	cs, err := crypto.NewCryptoServiceFromMaster(oldMaster, []string{"USER_EMAIL", "API_KEY"}, salt)
+	if err != nil {
+		t.Fatalf("NewCryptoServiceFromMaster failed: %v", err)
+	}
+
+	rotated, err := cs.RotateMaster(newMaster, salt)
+	if err != nil {
+		t.Fatalf("RotateMaster failed: %v", err)
+	}
+
+	oldKeys := cs.ExportKeys()
+	newKeys := rotated.ExportKeys()
+
+	if len(newKeys) != len(oldKeys) {
+		t.Fatalf("Expected rotated service to keep the same dataTypes, got %d vs %d", len(newKeys), len(oldKeys))
+	}
+	for dataType, oldKey := range oldKeys {
+		newKey, ok := newKeys[dataType]
+		if !ok {
+			t.Fatalf("Expected rotated service to still have dataType %q", dataType)
+		}
+		if hex.EncodeToString(newKey) == hex.EncodeToString(oldKey) {
+			t.Errorf("Expected %q subkey to change after RotateMaster", dataType)
+		}
+	}
+}
+
+func TestRotateMaster_PreservesPerDataTypeAlgorithm(t *testing.T) {
+	oldMaster := []byte("old master secret")
+	newMaster := []byte("new master secret")
+	salt := []byte("deployment-salt")
+
+	cs, err := crypto.NewCryptoServiceFromMaster(oldMaster, []string{"USER_EMAIL"}, salt)
+	if err != nil {
+		t.Fatalf("NewCryptoServiceFromMaster failed: %v", err)
+	}
+	keys := cs.ExportKeys()
+	cs, err = crypto.NewCryptoServiceWithAlgorithms(crypto.AlgKeyMap{
+		"USER_EMAIL": {Key: keys["USER_EMAIL"], Alg: crypto.AlgChaCha20Poly1305},
+	})
+	if err != nil {
+		t.Fatalf("NewCryptoServiceWithAlgorithms failed: %v", err)
+	}
+
+	rotated, err := cs.RotateMaster(newMaster, salt)
+	if err != nil {
+		t.Fatalf("RotateMaster failed: %v", err)
+	}
+
+	result, err := rotated.EncryptWithAlg("secret", "USER_EMAIL")
+	if err != nil {
+		t.Fatalf("EncryptWithAlg on rotated service failed: %v", err)
+	}
+	if result.Alg != crypto.AlgChaCha20Poly1305 {
+		t.Errorf("Expected RotateMaster to preserve AlgChaCha20Poly1305 for USER_EMAIL, got %v", result.Alg)
+	}
+}
@@ -0,0 +1,91 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+func TestOptions_WithAAD_RoundTrips(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	aad := []byte("user:42")
+	enc, err := cs.Encrypt("user@example.com", "EMAIL", crypto.WithAAD(aad))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if enc.AAD == "" {
+		t.Fatal("Expected EncryptResult.AAD to be populated")
+	}
+
+	dec, err := cs.Decrypt(enc.Encrypted, enc.IV, enc.AuthTag, "EMAIL", crypto.WithAAD(aad))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if dec != "user@example.com" {
+		t.Errorf("Expected %q, got %q", "user@example.com", dec)
+	}
+}
+
+func TestOptions_WithAAD_MismatchFails(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	enc, _ := cs.Encrypt("user@example.com", "EMAIL", crypto.WithAAD([]byte("user:42")))
+
+	_, err := cs.Decrypt(enc.Encrypted, enc.IV, enc.AuthTag, "EMAIL", crypto.WithAAD([]byte("user:43")))
+	if err == nil {
+		t.Fatal("Expected error for mismatched AAD")
+	}
+}
+
+func TestOptions_WithNonce_Deterministic(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	nonce := make([]byte, crypto.GCMNonceSize)
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+
+	enc1, err := cs.Encrypt("user@example.com", "EMAIL", crypto.WithNonce(nonce))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	enc2, err := cs.Encrypt("user@example.com", "EMAIL", crypto.WithNonce(nonce))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if enc1.Encrypted != enc2.Encrypted || enc1.IV != enc2.IV {
+		t.Error("Expected identical ciphertext/IV when nonce is fixed")
+	}
+}
+
+func TestOptions_WithNonce_InvalidSizeRejected(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	_, err := cs.Encrypt("user@example.com", "EMAIL", crypto.WithNonce([]byte("too-short")))
+	if err == nil {
+		t.Fatal("Expected error for invalid WithNonce length")
+	}
+}
+
+func TestOptions_BackwardsCompatibleWithoutOptions(t *testing.T) {
+	key := make([]byte, 32)
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"EMAIL": key})
+
+	enc, err := cs.Encrypt("user@example.com", "EMAIL")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	dec, err := cs.Decrypt(enc.Encrypted, enc.IV, enc.AuthTag, "EMAIL")
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if dec != "user@example.com" {
+		t.Errorf("Expected %q, got %q", "user@example.com", dec)
+	}
+}
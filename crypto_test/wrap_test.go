@@ -0,0 +1,69 @@
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/BLAgency/BLcrypto/crypto"
+)
+
+func TestWrapUnwrapKey_RoundTrips(t *testing.T) {
+	senderKey := make([]byte, 32)
+	for i := range senderKey {
+		senderKey[i] = byte(i + 1)
+	}
+	sender, _ := crypto.NewCryptoService(crypto.KeyMap{"SHARED_SECRET": senderKey})
+
+	recipientPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	envelope, err := sender.WrapKey("SHARED_SECRET", &recipientPriv.PublicKey)
+	if err != nil {
+		t.Fatalf("WrapKey failed: %v", err)
+	}
+
+	recipient, _ := crypto.NewCryptoService(crypto.KeyMap{})
+	if err := recipient.UnwrapKey("SHARED_SECRET", envelope, recipientPriv); err != nil {
+		t.Fatalf("UnwrapKey failed: %v", err)
+	}
+
+	plaintext := "hello from the sender"
+	enc, err := sender.Encrypt(plaintext, "SHARED_SECRET")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	dec, err := recipient.Decrypt(enc.Encrypted, enc.IV, enc.AuthTag, "SHARED_SECRET")
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if dec != plaintext {
+		t.Errorf("Expected %q, got %q", plaintext, dec)
+	}
+}
+
+func TestWrapKey_UnknownDataType(t *testing.T) {
+	cs, _ := crypto.NewCryptoService(crypto.KeyMap{"KNOWN": make([]byte, 32)})
+	recipientPriv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	_, err := cs.WrapKey("UNKNOWN", &recipientPriv.PublicKey)
+	if err == nil {
+		t.Fatal("Expected error for unknown data type")
+	}
+}
+
+func TestUnwrapKey_WrongRecipientFails(t *testing.T) {
+	sender, _ := crypto.NewCryptoService(crypto.KeyMap{"SHARED_SECRET": make([]byte, 32)})
+
+	realRecipientPriv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	envelope, _ := sender.WrapKey("SHARED_SECRET", &realRecipientPriv.PublicKey)
+
+	wrongRecipientPriv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	wrongRecipient, _ := crypto.NewCryptoService(crypto.KeyMap{})
+	if err := wrongRecipient.UnwrapKey("SHARED_SECRET", envelope, wrongRecipientPriv); err == nil {
+		t.Fatal("Expected error when unwrapping with the wrong private key")
+	}
+}